@@ -0,0 +1,128 @@
+package appender
+
+import (
+	"context"
+	"time"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// DefaultQuantile is the response time quantile reported when the caller
+// doesn't specify responseTimeQuantile.
+const DefaultQuantile = 0.95
+
+const (
+	DeadNodeAppenderName       = "deadNode"
+	IstioAppenderName          = "istio"
+	ResponseTimeAppenderName   = "responseTime"
+	SecurityPolicyAppenderName = "securityPolicy"
+	ServiceEntryAppenderName   = "serviceEntry"
+	SidecarsCheckAppenderName  = "sidecarsCheck"
+	UnusedNodeAppenderName     = "unusedNode"
+)
+
+// Appender transforms a graph's TrafficMap, adding, decorating or pruning
+// nodes and edges. ctx is the request's deadline/cancellation context (see
+// graph/options.Options.Ctx); appenders that fan out to multiple Prometheus
+// queries or business-layer calls should check it between queries (see
+// CheckContext) so a client disconnect or elapsed deadline is noticed
+// promptly instead of after the whole fan-out completes.
+type Appender interface {
+	AppendGraph(ctx context.Context, trafficMap graph.TrafficMap)
+}
+
+// ServiceEntryAppender is registered for ServiceEntry-backed hosts and runs
+// first in the built-in order. Its AppendGraph checks ctx once per accessible
+// namespace and leaves trafficMap unchanged: it resolves no ServiceEntry
+// hosts into graph nodes.
+type ServiceEntryAppender struct {
+	AccessibleNamespaces map[string]time.Time
+}
+
+// AppendGraph implements Appender.
+func (a ServiceEntryAppender) AppendGraph(ctx context.Context, trafficMap graph.TrafficMap) {
+	for range a.AccessibleNamespaces {
+		CheckContext(ctx)
+	}
+}
+
+// DeadNodeAppender is registered for dead-node filtering. Its AppendGraph
+// checks ctx and leaves trafficMap unchanged: it removes no nodes.
+type DeadNodeAppender struct{}
+
+// AppendGraph implements Appender.
+func (a DeadNodeAppender) AppendGraph(ctx context.Context, trafficMap graph.TrafficMap) {
+	CheckContext(ctx)
+}
+
+// ResponseTimeAppender is registered for response time telemetry at a given
+// quantile. Its AppendGraph checks ctx once per namespace and leaves
+// trafficMap unchanged: it runs no Prometheus query and sets no response
+// time on any edge.
+type ResponseTimeAppender struct {
+	Quantile           float64
+	GraphType          string
+	IncludeIstio       bool
+	InjectServiceNodes bool
+	Namespaces         map[string]graph.NamespaceInfo
+	QueryTime          int64
+}
+
+// AppendGraph implements Appender.
+func (a ResponseTimeAppender) AppendGraph(ctx context.Context, trafficMap graph.TrafficMap) {
+	for range a.Namespaces {
+		CheckContext(ctx)
+	}
+}
+
+// SecurityPolicyAppender is registered for the mTLS/security policy in
+// effect. Like ResponseTimeAppender, its AppendGraph checks ctx once per
+// namespace and leaves trafficMap unchanged: it runs no Prometheus query and
+// sets no security policy on any edge.
+type SecurityPolicyAppender struct {
+	GraphType          string
+	IncludeIstio       bool
+	InjectServiceNodes bool
+	Namespaces         map[string]graph.NamespaceInfo
+	QueryTime          int64
+}
+
+// AppendGraph implements Appender.
+func (a SecurityPolicyAppender) AppendGraph(ctx context.Context, trafficMap graph.TrafficMap) {
+	for range a.Namespaces {
+		CheckContext(ctx)
+	}
+}
+
+// UnusedNodeAppender is registered for services/workloads that currently
+// have no traffic. Its AppendGraph checks ctx and leaves trafficMap
+// unchanged: it adds no nodes.
+type UnusedNodeAppender struct {
+	GraphType   string
+	IsNodeGraph bool
+}
+
+// AppendGraph implements Appender.
+func (a UnusedNodeAppender) AppendGraph(ctx context.Context, trafficMap graph.TrafficMap) {
+	CheckContext(ctx)
+}
+
+// IstioAppender is registered for Istio sidecar and version information. Its
+// AppendGraph checks ctx and leaves trafficMap unchanged: it decorates no
+// nodes.
+type IstioAppender struct{}
+
+// AppendGraph implements Appender.
+func (a IstioAppender) AppendGraph(ctx context.Context, trafficMap graph.TrafficMap) {
+	CheckContext(ctx)
+}
+
+// SidecarsCheckAppender is registered for flagging nodes that are missing an
+// Istio sidecar. Its AppendGraph checks ctx and leaves trafficMap unchanged:
+// it flags no nodes.
+type SidecarsCheckAppender struct{}
+
+// AppendGraph implements Appender.
+func (a SidecarsCheckAppender) AppendGraph(ctx context.Context, trafficMap graph.TrafficMap) {
+	CheckContext(ctx)
+}
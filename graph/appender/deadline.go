@@ -0,0 +1,60 @@
+package appender
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// queryDeadline models a single read/write deadline the way netstack's
+// gonet adapter does: a channel that closes once, when the deadline elapses,
+// plus a timer driving the close. Nothing else closes the channel (see
+// stop). Appenders that fan out to multiple Prometheus queries can use one
+// of these per query to get a timeout distinct from the overall request
+// deadline carried in AppenderConfig.Ctx.
+type queryDeadline struct {
+	C     chan struct{}
+	once  sync.Once
+	timer *time.Timer
+}
+
+// newQueryDeadline starts a deadline that fires after d, or never fires if
+// d is zero.
+func newQueryDeadline(d time.Duration) *queryDeadline {
+	dl := &queryDeadline{C: make(chan struct{})}
+	if d > 0 {
+		dl.timer = time.AfterFunc(d, dl.expire)
+	}
+	return dl
+}
+
+func (dl *queryDeadline) expire() {
+	dl.once.Do(func() { close(dl.C) })
+}
+
+// stop releases the underlying timer. It does not close C; callers that
+// finished before the deadline fired should simply stop using dl.
+func (dl *queryDeadline) stop() {
+	if dl.timer != nil {
+		dl.timer.Stop()
+	}
+}
+
+// CheckContext surfaces ctx's cancellation as a structured graph.Error,
+// instead of letting an appender's Prometheus or business-layer call fail
+// partway through with an opaque "context canceled" wrapped deep in some
+// other error. Appenders that fan out multiple queries should call this
+// between queries so a client disconnect or elapsed deadline is noticed
+// promptly rather than after every fanned-out query completes.
+func CheckContext(ctx context.Context) {
+	switch ctx.Err() {
+	case nil:
+		return
+	case context.Canceled:
+		graph.Error("graph generation canceled: client disconnected")
+	case context.DeadlineExceeded:
+		graph.Error("graph generation canceled: deadline exceeded")
+	}
+}
@@ -0,0 +1,57 @@
+package appender
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCheckContextNoop(t *testing.T) {
+	CheckContext(context.Background())
+}
+
+func TestCheckContextCanceledPanics(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected CheckContext to surface a cancelled context as a graph.Error")
+		}
+	}()
+	CheckContext(ctx)
+}
+
+func TestCheckContextDeadlineExceededPanics(t *testing.T) {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected CheckContext to surface an elapsed deadline as a graph.Error")
+		}
+	}()
+	CheckContext(ctx)
+}
+
+func TestQueryDeadlineExpires(t *testing.T) {
+	dl := newQueryDeadline(10 * time.Millisecond)
+	defer dl.stop()
+
+	select {
+	case <-dl.C:
+	case <-time.After(time.Second):
+		t.Fatalf("expected queryDeadline to expire within 1s")
+	}
+}
+
+func TestQueryDeadlineZeroNeverExpires(t *testing.T) {
+	dl := newQueryDeadline(0)
+	defer dl.stop()
+
+	select {
+	case <-dl.C:
+		t.Fatalf("expected a zero-duration queryDeadline to never expire")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,104 @@
+package appender
+
+import (
+	"context"
+
+	"github.com/kiali/kiali/graph"
+)
+
+const FederationAppenderName = "federation"
+
+// ServiceMeshPeer mirrors the Maistra federation CRD of the same name: it
+// describes a remote mesh this mesh has a trust relationship with.
+type ServiceMeshPeer struct {
+	Name    string
+	Cluster string
+}
+
+// FederationAppender adds pseudo-nodes representing traffic that leaves or
+// enters the mesh through a federation gateway, instead of letting it appear
+// as an unknown external service. For each accessible namespace and each
+// configured peer in Peers (options.Options.Peers), it adds an egress node only if that
+// namespace actually exports services to the peer (per Exports), and an
+// ingress node only if it actually imports services from the peer (per
+// Imports) — it never draws a federation node for a namespace/peer pair with
+// no relationship, since that would suggest federation traffic that isn't
+// configured.
+//
+// Exports and Imports are expected to be derived from the peer's real
+// ServiceMeshPeer/ExportedServiceSet/ImportedServiceSet CRs. No
+// federation-aware business-layer client exists yet in this tree to read
+// those CRs, so until one lands, callers have nothing to populate Exports
+// and Imports with; AppendGraph then correctly adds no federation nodes at
+// all, rather than drawing misleading ones from Peers alone.
+type FederationAppender struct {
+	AccessibleNamespaces map[string]graph.NamespaceInfo
+	Peers                []graph.PeerInfo
+	// Exports maps a namespace to the set of peer names it exports
+	// services to.
+	Exports map[string]map[string]bool
+	// Imports maps a namespace to the set of peer names it imports
+	// services from.
+	Imports map[string]map[string]bool
+}
+
+// AppendGraph implements Appender. ctx is the request's deadline/cancellation
+// context (see graph/options.Options.Ctx); it is checked between namespaces
+// so a client disconnect or elapsed deadline is noticed promptly instead of
+// after every namespace's federation nodes have been added.
+func (a FederationAppender) AppendGraph(ctx context.Context, trafficMap graph.TrafficMap) {
+	if len(a.Peers) == 0 {
+		return
+	}
+
+	for namespace := range a.AccessibleNamespaces {
+		CheckContext(ctx)
+
+		for _, peer := range a.Peers {
+			if a.exportsTo(namespace, peer.Name) {
+				a.addEgressNode(trafficMap, namespace, peer)
+			}
+			if a.importsFrom(namespace, peer.Name) {
+				a.addIngressNode(trafficMap, namespace, peer)
+			}
+		}
+	}
+}
+
+// exportsTo reports whether namespace is configured to export services to
+// the peer named peerName.
+func (a FederationAppender) exportsTo(namespace string, peerName string) bool {
+	peers, found := a.Exports[namespace]
+	return found && peers[peerName]
+}
+
+// importsFrom reports whether namespace is configured to import services
+// from the peer named peerName.
+func (a FederationAppender) importsFrom(namespace string, peerName string) bool {
+	peers, found := a.Imports[namespace]
+	return found && peers[peerName]
+}
+
+func (a FederationAppender) addEgressNode(trafficMap graph.TrafficMap, namespace string, peer graph.PeerInfo) {
+	svc := peer.Name + "-egress"
+	id, nodeType := graph.Id(peer.Cluster, namespace, "", "", "", svc, graph.GraphTypeService)
+	node, found := trafficMap[id]
+	if !found {
+		node = graph.NewNode(id, nodeType, namespace, "", "", "", svc, graph.GraphTypeService)
+		trafficMap[id] = node
+	}
+	node.Metadata["isFederationEgress"] = true
+	node.Metadata["federationPeer"] = peer.Name
+}
+
+func (a FederationAppender) addIngressNode(trafficMap graph.TrafficMap, namespace string, peer graph.PeerInfo) {
+	svc := peer.Name + "-ingress"
+	id, nodeType := graph.Id(peer.Cluster, namespace, "", "", "", svc, graph.GraphTypeService)
+	node, found := trafficMap[id]
+	if !found {
+		node = graph.NewNode(id, nodeType, namespace, "", "", "", svc, graph.GraphTypeService)
+		trafficMap[id] = node
+	}
+	node.Metadata["isFederationIngress"] = true
+	node.Metadata["federationPeer"] = peer.Name
+}
@@ -0,0 +1,47 @@
+package appender
+
+import "testing"
+
+func TestFederationAppenderExportsTo(t *testing.T) {
+	a := FederationAppender{
+		Exports: map[string]map[string]bool{
+			"bookinfo": {"mesh-b": true},
+		},
+	}
+
+	if !a.exportsTo("bookinfo", "mesh-b") {
+		t.Fatalf("expected bookinfo to export to mesh-b")
+	}
+	if a.exportsTo("bookinfo", "mesh-c") {
+		t.Fatalf("expected bookinfo not to export to mesh-c, which has no configured relationship")
+	}
+	if a.exportsTo("other", "mesh-b") {
+		t.Fatalf("expected a namespace with no Exports entry at all not to export to any peer")
+	}
+}
+
+func TestFederationAppenderImportsFrom(t *testing.T) {
+	a := FederationAppender{
+		Imports: map[string]map[string]bool{
+			"bookinfo": {"mesh-b": true},
+		},
+	}
+
+	if !a.importsFrom("bookinfo", "mesh-b") {
+		t.Fatalf("expected bookinfo to import from mesh-b")
+	}
+	if a.importsFrom("bookinfo", "mesh-c") {
+		t.Fatalf("expected bookinfo not to import from mesh-c, which has no configured relationship")
+	}
+}
+
+func TestFederationAppenderNilRelationshipsExportNothing(t *testing.T) {
+	a := FederationAppender{}
+
+	if a.exportsTo("bookinfo", "mesh-b") {
+		t.Fatalf("expected a FederationAppender with no Exports data to never report an export relationship")
+	}
+	if a.importsFrom("bookinfo", "mesh-b") {
+		t.Fatalf("expected a FederationAppender with no Imports data to never report an import relationship")
+	}
+}
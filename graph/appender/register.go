@@ -0,0 +1,115 @@
+package appender
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// init registers the built-in appenders. Each registration here other than
+// federation's (which had no case there) is the one-file equivalent of what
+// used to be a case in options.parseAppenders's switch statement: the query
+// params and AppenderConfig fields a factory reads are exactly what that
+// appender's case used to read directly.
+func init() {
+	Register(RegistrationInfo{
+		Name:        ServiceEntryAppenderName,
+		Description: "ServiceEntry-backed hosts; runs before other appenders. Checks the request context only and leaves the graph unchanged.",
+	}, func(params url.Values, cfg AppenderConfig) (Appender, error) {
+		return ServiceEntryAppender{
+			AccessibleNamespaces: FlattenAccessibleNamespaces(cfg),
+		}, nil
+	})
+
+	Register(RegistrationInfo{
+		Name:        DeadNodeAppenderName,
+		Description: "Dead-node filtering. Checks the request context only and removes no nodes.",
+	}, func(params url.Values, cfg AppenderConfig) (Appender, error) {
+		return DeadNodeAppender{}, nil
+	})
+
+	Register(RegistrationInfo{
+		Name:        ResponseTimeAppenderName,
+		Description: "Response time telemetry at a given quantile. Checks the request context only and decorates no edges.",
+		Params: []ParamInfo{
+			{Name: "responseTimeQuantile", Description: "The quantile to report, e.g. 0.95", Default: fmt.Sprintf("%v", DefaultQuantile)},
+		},
+	}, func(params url.Values, cfg AppenderConfig) (Appender, error) {
+		quantile := DefaultQuantile
+		if _, ok := params["responseTimeQuantile"]; ok {
+			parsed, err := strconv.ParseFloat(params.Get("responseTimeQuantile"), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid responseTimeQuantile [%s]", params.Get("responseTimeQuantile"))
+			}
+			quantile = parsed
+		}
+		return ResponseTimeAppender{
+			Quantile:           quantile,
+			GraphType:          cfg.GraphType,
+			InjectServiceNodes: cfg.InjectServiceNodes,
+			IncludeIstio:       cfg.IncludeIstio,
+			Namespaces:         cfg.Namespaces,
+			QueryTime:          cfg.QueryTime,
+		}, nil
+	})
+
+	Register(RegistrationInfo{
+		Name:        SecurityPolicyAppenderName,
+		Description: "mTLS/security policy in effect. Checks the request context only and decorates no edges.",
+	}, func(params url.Values, cfg AppenderConfig) (Appender, error) {
+		return SecurityPolicyAppender{
+			GraphType:          cfg.GraphType,
+			IncludeIstio:       cfg.IncludeIstio,
+			InjectServiceNodes: cfg.InjectServiceNodes,
+			Namespaces:         cfg.Namespaces,
+			QueryTime:          cfg.QueryTime,
+		}, nil
+	})
+
+	Register(RegistrationInfo{
+		Name:        UnusedNodeAppenderName,
+		Description: "Services/workloads that currently have no traffic. Checks the request context only and adds no nodes.",
+	}, func(params url.Values, cfg AppenderConfig) (Appender, error) {
+		return UnusedNodeAppender{
+			GraphType:   cfg.GraphType,
+			IsNodeGraph: cfg.IsNodeGraph,
+		}, nil
+	})
+
+	Register(RegistrationInfo{
+		Name:        IstioAppenderName,
+		Description: "Istio sidecar and version information. Checks the request context only and decorates no nodes.",
+	}, func(params url.Values, cfg AppenderConfig) (Appender, error) {
+		return IstioAppender{}, nil
+	})
+
+	Register(RegistrationInfo{
+		Name:        SidecarsCheckAppenderName,
+		Description: "Nodes missing an Istio sidecar. Checks the request context only and flags no nodes.",
+	}, func(params url.Values, cfg AppenderConfig) (Appender, error) {
+		return SidecarsCheckAppender{}, nil
+	})
+
+	Register(RegistrationInfo{
+		Name:        FederationAppenderName,
+		Description: "Adds federation egress/ingress pseudo-nodes for configured mesh peers. Opt-in only.",
+		Params: []ParamInfo{
+			{Name: "federation", Description: "Must be \"true\" to enable; federation is never part of the default appender set.", Default: "false"},
+			{Name: "peers", Description: "Comma-separated list of federation peer names to scope to, each optionally suffixed \"@cluster\".", Default: ""},
+		},
+	}, func(params url.Values, cfg AppenderConfig) (Appender, error) {
+		federationEnabled, _ := strconv.ParseBool(params.Get("federation"))
+		if !federationEnabled {
+			return nil, nil
+		}
+		// Exports/Imports are left nil: no federation-aware business-layer
+		// client exists yet in this tree to resolve a namespace's real
+		// ExportedServiceSet/ImportedServiceSet CRs against cfg.Peers. Until
+		// one is wired in here, FederationAppender correctly draws no
+		// pseudo-nodes rather than drawing one unconditionally per peer.
+		return FederationAppender{
+			AccessibleNamespaces: cfg.Namespaces,
+			Peers:                cfg.Peers,
+		}, nil
+	})
+}
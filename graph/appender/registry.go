@@ -0,0 +1,127 @@
+package appender
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// AppenderConfig carries the subset of graph/options.Options that appender
+// factories need in order to build an Appender. It exists so that this
+// package doesn't need to import graph/options (which already imports this
+// package to reference registered appenders).
+type AppenderConfig struct {
+	AccessibleNamespaces map[graph.TenantNamespaceKey]graph.NamespaceAccess
+	Ctx                  context.Context // request context; cancelled on client disconnect or deadline
+	GraphType            string
+	IncludeIstio         bool
+	InjectServiceNodes   bool
+	IsNodeGraph          bool
+	Namespaces           map[string]graph.NamespaceInfo
+	Peers                []graph.PeerInfo
+	QueryTime            int64
+	TenantID             string
+}
+
+// ParamInfo documents a single query parameter an appender factory accepts.
+type ParamInfo struct {
+	Name        string
+	Description string
+	Default     string
+}
+
+// RegistrationInfo describes a registered appender for introspection by
+// GET /api/graph/appenders.
+type RegistrationInfo struct {
+	Name        string
+	Description string
+	Params      []ParamInfo
+}
+
+// Factory builds an Appender from the request's raw query params and the
+// resolved graph config. It returns an error rather than panicking so the
+// registry caller can decide how to surface a bad parameter.
+type Factory func(params url.Values, cfg AppenderConfig) (Appender, error)
+
+type registration struct {
+	info    RegistrationInfo
+	factory Factory
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]registration)
+)
+
+// Register adds an appender factory to the registry under info.Name. It is
+// intended to be called from an appender's init() function, making adding a
+// new appender a one-file change instead of editing parseAppenders's switch
+// statement. Register panics on a duplicate name since that indicates a
+// programming error, not a runtime condition.
+func Register(info RegistrationInfo, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, found := registry[info.Name]; found {
+		panic(fmt.Sprintf("appender [%s] is already registered", info.Name))
+	}
+	registry[info.Name] = registration{info: info, factory: factory}
+}
+
+// Get returns the factory registered under name, if any.
+func Get(name string) (Factory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	r, found := registry[name]
+	if !found {
+		return nil, false
+	}
+	return r.factory, true
+}
+
+// Names returns the names of all registered appenders.
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	return names
+}
+
+// List returns the registration info for every registered appender, for use
+// by GET /api/graph/appenders.
+func List() []RegistrationInfo {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	infos := make([]RegistrationInfo, 0, len(registry))
+	for _, r := range registry {
+		infos = append(infos, r.info)
+	}
+	return infos
+}
+
+// FlattenAccessibleNamespaces projects cfg.AccessibleNamespaces (keyed by
+// tenant+name, see graph.TenantNamespaceKey) down to the plain
+// map[string]time.Time that pre-tenant appenders such as ServiceEntryAppender
+// expect, scoped to cfg.TenantID. Namespaces belonging to other tenants are
+// dropped so a cross-tenant accessibility check can never leak through an
+// appender that isn't tenant-aware.
+func FlattenAccessibleNamespaces(cfg AppenderConfig) map[string]time.Time {
+	flat := make(map[string]time.Time, len(cfg.AccessibleNamespaces))
+	for key, access := range cfg.AccessibleNamespaces {
+		if key.TenantID != cfg.TenantID {
+			continue
+		}
+		flat[key.Name] = access.Created
+	}
+	return flat
+}
@@ -0,0 +1,50 @@
+package appender
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func TestFlattenAccessibleNamespacesScopesByTenant(t *testing.T) {
+	bookinfoCreated := time.Now().Add(-time.Hour)
+	otherCreated := time.Now().Add(-2 * time.Hour)
+
+	cfg := AppenderConfig{
+		TenantID: "tenant-a",
+		AccessibleNamespaces: map[graph.TenantNamespaceKey]graph.NamespaceAccess{
+			{TenantID: "tenant-a", Name: "bookinfo"}: {Name: "bookinfo", TenantID: "tenant-a", Created: bookinfoCreated},
+			{TenantID: "tenant-b", Name: "bookinfo"}: {Name: "bookinfo", TenantID: "tenant-b", Created: otherCreated},
+			{TenantID: "tenant-b", Name: "other"}:    {Name: "other", TenantID: "tenant-b", Created: otherCreated},
+		},
+	}
+
+	flat := FlattenAccessibleNamespaces(cfg)
+
+	if len(flat) != 1 {
+		t.Fatalf("expected exactly 1 namespace scoped to tenant-a, got %d: %v", len(flat), flat)
+	}
+	created, found := flat["bookinfo"]
+	if !found {
+		t.Fatalf("expected tenant-a's bookinfo namespace to be present")
+	}
+	if !created.Equal(bookinfoCreated) {
+		t.Fatalf("expected tenant-a's bookinfo creation time, got a different tenant's: %v", created)
+	}
+}
+
+func TestFlattenAccessibleNamespacesEmptyWhenNoMatch(t *testing.T) {
+	cfg := AppenderConfig{
+		TenantID: "tenant-a",
+		AccessibleNamespaces: map[graph.TenantNamespaceKey]graph.NamespaceAccess{
+			{TenantID: "tenant-b", Name: "bookinfo"}: {Name: "bookinfo", TenantID: "tenant-b", Created: time.Now()},
+		},
+	}
+
+	flat := FlattenAccessibleNamespaces(cfg)
+
+	if len(flat) != 0 {
+		t.Fatalf("expected no namespaces when tenant has none accessible, got %v", flat)
+	}
+}
@@ -0,0 +1,9 @@
+package graph
+
+// PeerInfo identifies a remote mesh that this mesh federates with, as
+// modeled by a Maistra ServiceMeshPeer custom resource. It is used to scope
+// a graph to traffic crossing a specific federation boundary.
+type PeerInfo struct {
+	Name    string
+	Cluster string
+}
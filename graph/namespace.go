@@ -0,0 +1,15 @@
+package graph
+
+import "time"
+
+// NamespaceInfo describes a namespace scoped into a single graph query: its
+// name, the tenant it was resolved under (see TenantNamespaceKey), and how
+// far back in time it is safe to query given when the namespace was created.
+// TenantID lets downstream Prometheus queries inject a tenant_id label
+// matcher so a query never crosses tenant boundaries even when namespace
+// names collide across tenants.
+type NamespaceInfo struct {
+	Name     string
+	TenantID string
+	Duration time.Duration
+}
@@ -0,0 +1,320 @@
+// Package discovery provides pluggable namespace discovery providers that
+// augment the set of namespaces available to graph generation beyond what
+// the local Kubernetes API server reports for the caller's token. This
+// mirrors the Prometheus service discovery model (file_sd, dns_sd, consul_sd):
+// each provider independently resolves a set of namespaces, and a composite
+// cache merges them with periodic re-sync so multi-mesh/multi-cluster graphs
+// can reference namespaces that aren't real k8s namespaces on this cluster.
+package discovery
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	consulapi "github.com/hashicorp/consul/api"
+	"gopkg.in/yaml.v2"
+
+	"github.com/kiali/kiali/log"
+)
+
+// NamespaceInfo describes a namespace discovered by a NamespaceDiscoverer.
+// Unlike graph.NamespaceInfo, it is not yet bound to a query duration; it is
+// the raw fact that a namespace exists, as reported by the provider.
+type NamespaceInfo struct {
+	Name    string
+	Labels  map[string]string
+	Cluster string
+	Created time.Time
+}
+
+// NamespaceDiscoverer resolves a set of namespaces from some external
+// source. Implementations must be safe to call repeatedly; the caller is
+// responsible for caching and re-sync scheduling.
+type NamespaceDiscoverer interface {
+	Discover() (map[string]NamespaceInfo, error)
+}
+
+// fileEntry is the shape of a single record in a file-based SD source file.
+type fileEntry struct {
+	Namespace string            `yaml:"namespace" json:"namespace"`
+	Labels    map[string]string `yaml:"labels" json:"labels"`
+	Cluster   string            `yaml:"cluster" json:"cluster"`
+}
+
+// FileDiscoverer discovers namespaces from a YAML or JSON file on disk,
+// re-reading it whenever fsnotify reports a change. This is the SD analog
+// of Prometheus's file_sd_config.
+type FileDiscoverer struct {
+	Path string
+
+	mu      sync.Mutex
+	watcher *fsnotify.Watcher
+}
+
+// Discover reads and parses the configured file.
+func (d *FileDiscoverer) Discover() (map[string]NamespaceInfo, error) {
+	raw, err := ioutil.ReadFile(d.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read namespace file_sd source [%s]: %s", d.Path, err)
+	}
+
+	var entries []fileEntry
+	if err := yaml.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse namespace file_sd source [%s]: %s", d.Path, err)
+	}
+
+	result := make(map[string]NamespaceInfo, len(entries))
+	for _, e := range entries {
+		result[e.Namespace] = NamespaceInfo{
+			Name:    e.Namespace,
+			Labels:  e.Labels,
+			Cluster: e.Cluster,
+			Created: time.Now(),
+		}
+	}
+	return result, nil
+}
+
+// Watch starts an fsnotify watch on the source file and invokes onChange
+// whenever the file is written or created. The caller is expected to call
+// Discover again in response. Watch is a no-op if already watching.
+func (d *FileDiscoverer) Watch(onChange func()) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.watcher != nil {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to start watcher for namespace file_sd source [%s]: %s", d.Path, err)
+	}
+	if err := watcher.Add(d.Path); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch namespace file_sd source [%s]: %s", d.Path, err)
+	}
+
+	d.watcher = watcher
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+					onChange()
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Errorf("namespace file_sd watcher error for [%s]: %s", d.Path, err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Close stops the underlying fsnotify watch, if any.
+func (d *FileDiscoverer) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.watcher == nil {
+		return nil
+	}
+	err := d.watcher.Close()
+	d.watcher = nil
+	return err
+}
+
+// DNSDiscoverer resolves a DNS SRV record into a set of namespaces, one per
+// target host, for referencing namespaces that live in an external mesh.
+// This is the SD analog of Prometheus's dns_sd_config with type SRV.
+type DNSDiscoverer struct {
+	// Name is the SRV record name to resolve, e.g. "_istio-mesh._tcp.example.com".
+	Name string
+	// Cluster is attached to every namespace resolved from this record.
+	Cluster string
+}
+
+// Discover resolves the configured SRV record.
+func (d *DNSDiscoverer) Discover() (map[string]NamespaceInfo, error) {
+	_, srvs, err := net.LookupSRV("", "", d.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve namespace dns_sd record [%s]: %s", d.Name, err)
+	}
+
+	now := time.Now()
+	result := make(map[string]NamespaceInfo, len(srvs))
+	for _, srv := range srvs {
+		name := strings.TrimSuffix(srv.Target, ".")
+		result[name] = NamespaceInfo{
+			Name:    name,
+			Cluster: d.Cluster,
+			Created: now,
+		}
+	}
+	return result, nil
+}
+
+// ConsulDiscoverer discovers namespaces from a Consul catalog, mapping each
+// service's tags to a synthetic namespace entry. This is the SD analog of
+// Prometheus's consul_sd_config.
+type ConsulDiscoverer struct {
+	// Address is the Consul HTTP API address, e.g. "consul.istio-system:8500".
+	Address string
+	// TagPrefix filters which service tags are treated as namespace names,
+	// e.g. a tag "namespace=foo" yields the namespace "foo".
+	TagPrefix string
+
+	client *consulapi.Client
+}
+
+// Discover queries the Consul catalog for services and derives a namespace
+// per distinct tag carrying the configured TagPrefix.
+func (d *ConsulDiscoverer) Discover() (map[string]NamespaceInfo, error) {
+	if d.client == nil {
+		client, err := consulapi.NewClient(&consulapi.Config{Address: d.Address})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create consul_sd client for [%s]: %s", d.Address, err)
+		}
+		d.client = client
+	}
+
+	services, _, err := d.client.Catalog().Services(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consul_sd catalog [%s]: %s", d.Address, err)
+	}
+
+	now := time.Now()
+	result := make(map[string]NamespaceInfo)
+	for service, tags := range services {
+		for _, tag := range tags {
+			if !strings.HasPrefix(tag, d.TagPrefix) {
+				continue
+			}
+			name := strings.TrimPrefix(tag, d.TagPrefix)
+			if name == "" {
+				continue
+			}
+			result[name] = NamespaceInfo{
+				Name:    name,
+				Cluster: service,
+				Created: now,
+			}
+		}
+	}
+	return result, nil
+}
+
+// watchableDiscoverer is implemented by providers, such as FileDiscoverer,
+// that can push an immediate change notification instead of waiting for the
+// next ResyncPeriod tick.
+type watchableDiscoverer interface {
+	Watch(onChange func()) error
+	Close() error
+}
+
+// CachingDiscoverer composes one or more NamespaceDiscoverer providers
+// behind an in-memory cache, re-syncing on a fixed interval so repeated
+// graph requests don't pay the cost of hitting every provider. Providers
+// that also implement watchableDiscoverer additionally trigger an immediate
+// refresh on change, rather than waiting for the next ResyncPeriod tick.
+type CachingDiscoverer struct {
+	Providers    []NamespaceDiscoverer
+	ResyncPeriod time.Duration
+
+	mu      sync.RWMutex
+	cache   map[string]NamespaceInfo
+	done    chan struct{}
+	watched []watchableDiscoverer
+}
+
+// Start performs an initial sync and then re-syncs on ResyncPeriod until
+// Stop is called. It also starts a watch on any provider that supports one,
+// refreshing immediately on a reported change. A non-positive ResyncPeriod
+// skips the periodic re-sync entirely rather than passing it to
+// time.NewTicker (which panics on a non-positive interval); this is a valid
+// configuration for a deployment that relies solely on watched providers.
+func (c *CachingDiscoverer) Start() {
+	c.refresh()
+
+	for _, p := range c.Providers {
+		watchable, ok := p.(watchableDiscoverer)
+		if !ok {
+			continue
+		}
+		if err := watchable.Watch(c.refresh); err != nil {
+			log.Errorf("namespace discovery provider watch failed: %s", err)
+			continue
+		}
+		c.watched = append(c.watched, watchable)
+	}
+
+	c.done = make(chan struct{})
+	if c.ResyncPeriod <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(c.ResyncPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				c.refresh()
+			case <-c.done:
+				return
+			}
+		}
+	}()
+}
+
+// Stop halts the re-sync loop and closes any watches started by Start.
+func (c *CachingDiscoverer) Stop() {
+	if c.done != nil {
+		close(c.done)
+	}
+	for _, w := range c.watched {
+		if err := w.Close(); err != nil {
+			log.Errorf("namespace discovery provider watch close failed: %s", err)
+		}
+	}
+	c.watched = nil
+}
+
+// Get returns the most recently synced set of discovered namespaces.
+func (c *CachingDiscoverer) Get() map[string]NamespaceInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := make(map[string]NamespaceInfo, len(c.cache))
+	for k, v := range c.cache {
+		result[k] = v
+	}
+	return result
+}
+
+func (c *CachingDiscoverer) refresh() {
+	merged := make(map[string]NamespaceInfo)
+	for _, p := range c.Providers {
+		discovered, err := p.Discover()
+		if err != nil {
+			log.Errorf("namespace discovery provider failed: %s", err)
+			continue
+		}
+		for name, info := range discovered {
+			merged[name] = info
+		}
+	}
+
+	c.mu.Lock()
+	c.cache = merged
+	c.mu.Unlock()
+}
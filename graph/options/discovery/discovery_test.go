@@ -0,0 +1,109 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// stubWatchableDiscoverer lets tests trigger an onChange callback directly,
+// without depending on real fsnotify timing.
+type stubWatchableDiscoverer struct {
+	namespaces map[string]NamespaceInfo
+	onChange   func()
+	closed     bool
+}
+
+func (d *stubWatchableDiscoverer) Discover() (map[string]NamespaceInfo, error) {
+	result := make(map[string]NamespaceInfo, len(d.namespaces))
+	for k, v := range d.namespaces {
+		result[k] = v
+	}
+	return result, nil
+}
+
+func (d *stubWatchableDiscoverer) Watch(onChange func()) error {
+	d.onChange = onChange
+	return nil
+}
+
+func (d *stubWatchableDiscoverer) Close() error {
+	d.closed = true
+	return nil
+}
+
+func TestCachingDiscovererRefreshesImmediatelyOnWatchedChange(t *testing.T) {
+	stub := &stubWatchableDiscoverer{namespaces: map[string]NamespaceInfo{
+		"bookinfo": {Name: "bookinfo"},
+	}}
+
+	c := &CachingDiscoverer{Providers: []NamespaceDiscoverer{stub}, ResyncPeriod: time.Hour}
+	c.Start()
+	defer c.Stop()
+
+	if _, found := c.Get()["bookinfo"]; !found {
+		t.Fatalf("expected initial sync to discover bookinfo")
+	}
+
+	stub.namespaces["details"] = NamespaceInfo{Name: "details"}
+	if stub.onChange == nil {
+		t.Fatalf("expected Start to register a Watch callback on a watchable provider")
+	}
+	stub.onChange()
+
+	if _, found := c.Get()["details"]; !found {
+		t.Fatalf("expected the watch callback to trigger an immediate refresh picking up details, without waiting for ResyncPeriod")
+	}
+}
+
+func TestCachingDiscovererStopClosesWatches(t *testing.T) {
+	stub := &stubWatchableDiscoverer{namespaces: map[string]NamespaceInfo{}}
+
+	c := &CachingDiscoverer{Providers: []NamespaceDiscoverer{stub}, ResyncPeriod: time.Hour}
+	c.Start()
+	c.Stop()
+
+	if !stub.closed {
+		t.Fatalf("expected Stop to close watches started by Start")
+	}
+}
+
+func TestCachingDiscovererZeroResyncPeriodDoesNotPanic(t *testing.T) {
+	stub := &stubWatchableDiscoverer{namespaces: map[string]NamespaceInfo{
+		"bookinfo": {Name: "bookinfo"},
+	}}
+
+	c := &CachingDiscoverer{Providers: []NamespaceDiscoverer{stub}, ResyncPeriod: 0}
+	c.Start()
+	defer c.Stop()
+
+	if _, found := c.Get()["bookinfo"]; !found {
+		t.Fatalf("expected initial sync to still run with a zero ResyncPeriod")
+	}
+}
+
+func TestFileDiscovererWatchTriggersOnChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "namespaces.yaml")
+	if err := os.WriteFile(path, []byte("- namespace: bookinfo\n"), 0644); err != nil {
+		t.Fatalf("failed to write namespace source file: %s", err)
+	}
+
+	d := &FileDiscoverer{Path: path}
+	changed := make(chan struct{}, 1)
+	if err := d.Watch(func() { changed <- struct{}{} }); err != nil {
+		t.Fatalf("Watch failed: %s", err)
+	}
+	defer d.Close()
+
+	if err := os.WriteFile(path, []byte("- namespace: bookinfo\n- namespace: details\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite namespace source file: %s", err)
+	}
+
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected Watch to report the file rewrite within 5s")
+	}
+}
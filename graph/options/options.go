@@ -2,6 +2,7 @@
 package options
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -15,6 +16,7 @@ import (
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/appender"
+	"github.com/kiali/kiali/graph/options/discovery"
 )
 
 const (
@@ -50,19 +52,58 @@ type VendorOptions struct {
 	Duration  time.Duration
 	GraphType string
 	GroupBy   string
-	QueryTime int64 // unix time in seconds
+	Peers     []graph.PeerInfo // federated mesh peers to scope the rendered subgraph to. Default none.
+	QueryTime int64            // unix time in seconds
 }
 
 // Options are all supported graph generation options.
 type Options struct {
-	AccessibleNamespaces map[string]time.Time
+	AccessibleNamespaces map[graph.TenantNamespaceKey]graph.NamespaceAccess
 	Appenders            []appender.Appender
-	IncludeIstio         bool // include istio-system services. Ignored for istio-system ns. Default false.
-	InjectServiceNodes   bool // inject destination service nodes between source and destination nodes.
+	Ctx                  context.Context // derived from the request context; cancelled on client disconnect or Deadline
+	Deadline             time.Time       // zero if the request specified no deadline
+	IncludeIstio         bool            // include istio-system services. Ignored for istio-system ns. Default false.
+	InjectServiceNodes   bool            // inject destination service nodes between source and destination nodes.
 	Namespaces           map[string]graph.NamespaceInfo
+	Peers                []graph.PeerInfo // federated mesh peers to render egress/ingress nodes for. Default none.
+	TenantID             string           // tenant the request was scoped to; DefaultTenantID if none was given.
 	Vendor               string
 	NodeOptions
 	VendorOptions
+
+	cancel context.CancelFunc
+}
+
+// Cancel releases the resources backing o.Ctx. The graph HTTP handler must
+// defer o.Cancel() immediately after NewOptions returns; that call site is
+// outside this package (it lives in the handlers package, which doesn't yet
+// call NewOptions directly in this tree) and is a follow-up dependency of
+// this change, not something NewOptions can enforce on its own. Until that
+// handler wiring lands, a deadline-bearing context.WithDeadline timer is
+// only released when it fires on its own, not when the request finishes.
+func (o *Options) Cancel() {
+	if o.cancel != nil {
+		o.cancel()
+	}
+}
+
+// ResolveTenantID determines the tenant a request is scoped to: the
+// X-Kiali-Tenant header, then a "tenant" path variable (mux.Vars), then a
+// "tenant" query param, then graph.DefaultTenantID if none of those are set.
+// Every handler that resolves tenant-scoped data (graph generation,
+// namespace discovery, ...) must use this so they agree on which tenant a
+// given request belongs to.
+func ResolveTenantID(r *http.Request) string {
+	if tenantID := r.Header.Get("X-Kiali-Tenant"); tenantID != "" {
+		return tenantID
+	}
+	if tenantID := mux.Vars(r)["tenant"]; tenantID != "" {
+		return tenantID
+	}
+	if tenantID := r.URL.Query().Get("tenant"); tenantID != "" {
+		return tenantID
+	}
+	return graph.DefaultTenantID
 }
 
 func NewOptions(r *http.Request) Options {
@@ -74,12 +115,18 @@ func NewOptions(r *http.Request) Options {
 	version := vars["version"]
 	workload := vars["workload"]
 
+	// Resolve the tenant this request is scoped to. Legacy single-tenant
+	// callers send neither the header nor the path/query var and get the
+	// implicit default tenant, so existing requests keep working unchanged.
+	tenantID := ResolveTenantID(r)
+
 	// query params
 	params := r.URL.Query()
 	var duration model.Duration
 	var includeIstio bool
 	var injectServiceNodes bool
 	var queryTime int64
+	deadlineString := params.Get("deadline")
 	durationString := params.Get("duration")
 	graphType := params.Get("graphType")
 	groupBy := params.Get("groupBy")
@@ -145,6 +192,24 @@ func NewOptions(r *http.Request) Options {
 		graph.BadRequest(fmt.Sprintf("Invalid vendor [%s]", vendor))
 	}
 
+	// Process the deadline option: if given, bound r.Context() with it so
+	// long-running Prometheus queries and business-layer calls are
+	// cancelled promptly once it elapses or the client disconnects.
+	ctx := r.Context()
+	cancel := func() {}
+	var requestDeadline time.Time
+	if deadlineString != "" {
+		deadlineDuration, deadlineErr := model.ParseDuration(deadlineString)
+		if deadlineErr != nil {
+			graph.BadRequest(fmt.Sprintf("Invalid deadline [%s]", deadlineString))
+		}
+		requestDeadline = time.Now().Add(time.Duration(deadlineDuration))
+		ctx, cancel = context.WithDeadline(ctx, requestDeadline)
+	}
+
+	// Process peers options (federated mesh peers to scope federation nodes/edges to):
+	peers := parsePeers(params.Get("peers"))
+
 	// Process namespaces options:
 	namespaceMap := make(map[string]graph.NamespaceInfo)
 
@@ -160,7 +225,7 @@ func NewOptions(r *http.Request) Options {
 		graph.Error("token missing in request context")
 	}
 
-	accessibleNamespaces := getAccessibleNamespaces(token)
+	accessibleNamespaces := getAccessibleNamespaces(token, tenantID)
 
 	// If path variable is set then it is the only relevant namespace (it's a node graph)
 	// Else if namespaces query param is set it specifies the relevant namespaces
@@ -175,10 +240,12 @@ func NewOptions(r *http.Request) Options {
 
 	for _, namespaceToken := range strings.Split(namespaces, ",") {
 		namespaceToken = strings.TrimSpace(namespaceToken)
-		if creationTime, found := accessibleNamespaces[namespaceToken]; found {
+		key := graph.TenantNamespaceKey{TenantID: tenantID, Name: namespaceToken}
+		if access, found := accessibleNamespaces[key]; found {
 			namespaceMap[namespaceToken] = graph.NamespaceInfo{
 				Name:     namespaceToken,
-				Duration: resolveNamespaceDuration(creationTime, time.Duration(duration), queryTime),
+				TenantID: tenantID,
+				Duration: resolveNamespaceDuration(access.Created, time.Duration(duration), queryTime),
 			}
 		} else {
 			graph.Forbidden(fmt.Sprintf("Requested namespace [%s] is not accessible.", namespaceToken))
@@ -192,10 +259,15 @@ func NewOptions(r *http.Request) Options {
 
 	options := Options{
 		AccessibleNamespaces: accessibleNamespaces,
+		Ctx:                  ctx,
+		Deadline:             requestDeadline,
 		IncludeIstio:         includeIstio,
 		InjectServiceNodes:   injectServiceNodes,
 		Namespaces:           namespaceMap,
+		Peers:                peers,
+		TenantID:             tenantID,
 		Vendor:               vendor,
+		cancel:               cancel,
 		NodeOptions: NodeOptions{
 			App:       app,
 			Namespace: namespace,
@@ -207,6 +279,7 @@ func NewOptions(r *http.Request) Options {
 			Duration:  time.Duration(duration),
 			GraphType: graphType,
 			GroupBy:   groupBy,
+			Peers:     peers,
 			QueryTime: queryTime,
 		},
 	}
@@ -229,106 +302,170 @@ func (o *Options) GetGraphKind() string {
 	}
 }
 
+// appenderOrder is the canonical run order for the built-in appenders:
+// pre-process service nodes first (service_entry), then filter dead nodes,
+// then run appenders that don't apply to unused services, then add orphan
+// (unused) services, then run everything else. Federation runs last since
+// it only adds pseudo-nodes for explicitly configured peers. Any appender
+// registered under a name not listed here runs after these, in registry
+// iteration order, which is acceptable since third-party appenders have no
+// ordering relationship with the built-ins.
+var appenderOrder = []string{
+	appender.ServiceEntryAppenderName,
+	appender.DeadNodeAppenderName,
+	appender.ResponseTimeAppenderName,
+	appender.SecurityPolicyAppenderName,
+	appender.UnusedNodeAppenderName,
+	appender.IstioAppenderName,
+	appender.SidecarsCheckAppenderName,
+	appender.FederationAppenderName,
+}
+
+// parseAppenders resolves the requested appender names against the appender
+// registry (see appender.Register) and builds the ordered list of Appenders
+// to run for this graph request. Adding a new appender is a one-file change
+// in the graph/appender package; this function no longer needs to know the
+// set of appenders that exist, only the order of the built-in ones.
 func parseAppenders(params url.Values, o Options) []appender.Appender {
 	requestedAppenders := make(map[string]bool)
 	allAppenders := false
 	if _, ok := params["appenders"]; ok {
 		for _, requestedAppender := range strings.Split(params.Get("appenders"), ",") {
-			switch strings.TrimSpace(requestedAppender) {
-			case appender.DeadNodeAppenderName:
-				requestedAppenders[appender.DeadNodeAppenderName] = true
-			case appender.ServiceEntryAppenderName:
-				requestedAppenders[appender.ServiceEntryAppenderName] = true
-			case appender.IstioAppenderName:
-				requestedAppenders[appender.IstioAppenderName] = true
-			case appender.ResponseTimeAppenderName:
-				requestedAppenders[appender.ResponseTimeAppenderName] = true
-			case appender.SecurityPolicyAppenderName:
-				requestedAppenders[appender.SecurityPolicyAppenderName] = true
-			case appender.SidecarsCheckAppenderName:
-				requestedAppenders[appender.SidecarsCheckAppenderName] = true
-			case appender.UnusedNodeAppenderName:
-				requestedAppenders[appender.UnusedNodeAppenderName] = true
-			case "":
-				// skip
-			default:
-				graph.BadRequest(fmt.Sprintf("Invalid appender [%s]", strings.TrimSpace(requestedAppender)))
+			name := strings.TrimSpace(requestedAppender)
+			if name == "" {
+				continue
 			}
+			if _, found := appender.Get(name); !found {
+				graph.BadRequest(fmt.Sprintf("Invalid appender [%s]", name))
+			}
+			requestedAppenders[name] = true
 		}
 	} else {
 		allAppenders = true
 	}
 
-	// The appender order is important
-	// To pre-process service nodes run service_entry appender first
-	// To reduce processing, filter dead nodes next
-	// To reduce processing, next run appenders that don't apply to unused services
-	// Add orphan (unused) services
-	// Run remaining appenders
+	cfg := appender.AppenderConfig{
+		AccessibleNamespaces: o.AccessibleNamespaces,
+		Ctx:                  o.Ctx,
+		GraphType:            o.GraphType,
+		IncludeIstio:         o.IncludeIstio,
+		InjectServiceNodes:   o.InjectServiceNodes,
+		IsNodeGraph:          o.App != "" || o.Workload != "" || o.Service != "",
+		Namespaces:           o.Namespaces,
+		Peers:                o.Peers,
+		QueryTime:            o.QueryTime,
+		TenantID:             o.TenantID,
+	}
+
 	var appenders []appender.Appender
+	built := make(map[string]bool)
 
-	if _, ok := requestedAppenders[appender.ServiceEntryAppenderName]; ok || allAppenders {
-		a := appender.ServiceEntryAppender{
-			AccessibleNamespaces: o.AccessibleNamespaces,
+	buildIfRequested := func(name string) {
+		if built[name] {
+			return
 		}
-		appenders = append(appenders, a)
-	}
-	if _, ok := requestedAppenders[appender.DeadNodeAppenderName]; ok || allAppenders {
-		a := appender.DeadNodeAppender{}
-		appenders = append(appenders, a)
-	}
-	if _, ok := requestedAppenders[appender.ResponseTimeAppenderName]; ok || allAppenders {
-		quantile := appender.DefaultQuantile
-		if _, ok := params["responseTimeQuantile"]; ok {
-			if responseTimeQuantile, err := strconv.ParseFloat(params.Get("responseTimeQuantile"), 64); err == nil {
-				quantile = responseTimeQuantile
+		_, explicitlyRequested := requestedAppenders[name]
+
+		// Federation is opt-in only: it must be named explicitly in
+		// appenders, never implied by the "run everything" default.
+		if name == appender.FederationAppenderName {
+			if allAppenders || !explicitlyRequested {
+				return
 			}
+		} else if !explicitlyRequested && !allAppenders {
+			return
 		}
-		a := appender.ResponseTimeAppender{
-			Quantile:           quantile,
-			GraphType:          o.GraphType,
-			InjectServiceNodes: o.InjectServiceNodes,
-			IncludeIstio:       o.IncludeIstio,
-			Namespaces:         o.Namespaces,
-			QueryTime:          o.QueryTime,
+
+		factory, found := appender.Get(name)
+		if !found {
+			return
 		}
-		appenders = append(appenders, a)
-	}
-	if _, ok := requestedAppenders[appender.SecurityPolicyAppenderName]; ok || allAppenders {
-		a := appender.SecurityPolicyAppender{
-			GraphType:          o.GraphType,
-			IncludeIstio:       o.IncludeIstio,
-			InjectServiceNodes: o.InjectServiceNodes,
-			Namespaces:         o.Namespaces,
-			QueryTime:          o.QueryTime,
+		a, err := factory(params, cfg)
+		if err != nil {
+			graph.BadRequest(err.Error())
 		}
-		appenders = append(appenders, a)
-	}
-	if _, ok := requestedAppenders[appender.UnusedNodeAppenderName]; ok || allAppenders {
-		hasNodeOptions := o.App != "" || o.Workload != "" || o.Service != ""
-		a := appender.UnusedNodeAppender{
-			GraphType:   o.GraphType,
-			IsNodeGraph: hasNodeOptions,
+		if a == nil {
+			// A factory can decline to build its appender even when
+			// explicitly requested, e.g. federation's own "federation=true"
+			// gate. Silently dropping an appender the caller named by name
+			// would be surprising, so reject it the same way an unknown
+			// appender name or a bad param would be.
+			if explicitlyRequested {
+				graph.BadRequest(fmt.Sprintf("Appender [%s] was requested but could not be built; check its required params.", name))
+			}
+			return
 		}
 		appenders = append(appenders, a)
+		built[name] = true
 	}
-	if _, ok := requestedAppenders[appender.IstioAppenderName]; ok || allAppenders {
-		a := appender.IstioAppender{}
-		appenders = append(appenders, a)
+
+	for _, name := range appenderOrder {
+		buildIfRequested(name)
 	}
-	if _, ok := requestedAppenders[appender.SidecarsCheckAppenderName]; ok || allAppenders {
-		a := appender.SidecarsCheckAppender{}
-		appenders = append(appenders, a)
+	for _, name := range appender.Names() {
+		buildIfRequested(name)
 	}
 
 	return appenders
 }
 
-// getAccessibleNamespaces returns a Set of all namespaces accessible to the user.
-// The Set is implemented using the map convention. Each map entry is set to the
-// creation timestamp of the namespace, to be used to ensure valid time ranges for
-// queries against the namespace.
-func getAccessibleNamespaces(token string) map[string]time.Time {
+// parsePeers parses the "peers" query param into graph.PeerInfo values: a
+// comma-separated list of peer names, each optionally suffixed "@cluster"
+// when the peer lives in a cluster other than the local one, e.g.
+// "mesh-b,mesh-c@remote-cluster".
+func parsePeers(peersString string) []graph.PeerInfo {
+	if peersString == "" {
+		return nil
+	}
+
+	var peers []graph.PeerInfo
+	for _, peerToken := range strings.Split(peersString, ",") {
+		peerToken = strings.TrimSpace(peerToken)
+		if peerToken == "" {
+			continue
+		}
+		peerName, peerCluster := peerToken, ""
+		if idx := strings.Index(peerToken, "@"); idx >= 0 {
+			peerName, peerCluster = peerToken[:idx], peerToken[idx+1:]
+		}
+		peers = append(peers, graph.PeerInfo{Name: peerName, Cluster: peerCluster})
+	}
+	return peers
+}
+
+// namespaceDiscoveryCache optionally augments the namespaces visible to graph
+// generation with namespaces discovered from pluggable, server-configured
+// sources (file, DNS, Consul). It is nil unless ConfigureNamespaceDiscovery
+// is called, in which case discovered namespaces are merged in alongside
+// whatever the local Kubernetes API server reports for the caller's token.
+var namespaceDiscoveryCache *discovery.CachingDiscoverer
+
+// ConfigureNamespaceDiscovery wires the given namespace discovery providers
+// into getAccessibleNamespaces, re-syncing them every resyncPeriod. It is
+// intended to be called once at server startup.
+func ConfigureNamespaceDiscovery(providers []discovery.NamespaceDiscoverer, resyncPeriod time.Duration) {
+	if namespaceDiscoveryCache != nil {
+		namespaceDiscoveryCache.Stop()
+	}
+	namespaceDiscoveryCache = &discovery.CachingDiscoverer{
+		Providers:    providers,
+		ResyncPeriod: resyncPeriod,
+	}
+	namespaceDiscoveryCache.Start()
+}
+
+// getAccessibleNamespaces returns a Set of all namespaces accessible to the
+// user, scoped to tenantID. The Set is implemented using the map convention,
+// keyed by TenantNamespaceKey so that two tenants sharing a cluster can each
+// have a namespace of the same name without either seeing the other's graph
+// nodes. Each entry carries the creation timestamp of the namespace, to be
+// used to ensure valid time ranges for queries against the namespace. In
+// addition to the namespaces the local Kubernetes API server reports for the
+// caller's token, any namespaces surfaced by a configured
+// namespaceDiscoveryCache (see ConfigureNamespaceDiscovery) are merged in
+// under tenantID, allowing graphs to reference namespaces from other
+// meshes/clusters.
+func getAccessibleNamespaces(token string, tenantID string) map[graph.TenantNamespaceKey]graph.NamespaceAccess {
 	// Get the namespaces
 	business, err := business.Get(token)
 	graph.CheckError(err)
@@ -337,9 +474,27 @@ func getAccessibleNamespaces(token string) map[string]time.Time {
 	graph.CheckError(err)
 
 	// Create a map to store the namespaces
-	namespaceMap := make(map[string]time.Time)
+	namespaceMap := make(map[graph.TenantNamespaceKey]graph.NamespaceAccess)
 	for _, namespace := range namespaces {
-		namespaceMap[namespace.Name] = namespace.CreationTimestamp
+		key := graph.TenantNamespaceKey{TenantID: tenantID, Name: namespace.Name}
+		namespaceMap[key] = graph.NamespaceAccess{
+			Name:     namespace.Name,
+			TenantID: tenantID,
+			Created:  namespace.CreationTimestamp,
+		}
+	}
+
+	if namespaceDiscoveryCache != nil {
+		for name, info := range namespaceDiscoveryCache.Get() {
+			key := graph.TenantNamespaceKey{TenantID: tenantID, Name: name}
+			if _, found := namespaceMap[key]; !found {
+				namespaceMap[key] = graph.NamespaceAccess{
+					Name:     name,
+					TenantID: tenantID,
+					Created:  info.Created,
+				}
+			}
+		}
 	}
 
 	return namespaceMap
@@ -367,3 +522,32 @@ func resolveNamespaceDuration(nsCreationTime time.Time, requestedRange time.Dura
 
 	return resolvedBound
 }
+
+// NamespaceDiscoveryInfo describes one namespace accessible to graph
+// generation, for use by GET /api/graph/namespaces.
+type NamespaceDiscoveryInfo struct {
+	Name     string
+	TenantID string
+	Created  time.Time
+	// MaxDuration is the longest graph duration that can be requested for
+	// this namespace as of now, without querying before its creation time.
+	MaxDuration time.Duration
+}
+
+// DiscoverNamespaces returns every namespace the given token can see within
+// tenantID, including any discovered via ConfigureNamespaceDiscovery, along
+// with the duration bound a graph request against it is subject to.
+func DiscoverNamespaces(token string, tenantID string) []NamespaceDiscoveryInfo {
+	accessibleNamespaces := getAccessibleNamespaces(token, tenantID)
+
+	infos := make([]NamespaceDiscoveryInfo, 0, len(accessibleNamespaces))
+	for _, access := range accessibleNamespaces {
+		infos = append(infos, NamespaceDiscoveryInfo{
+			Name:        access.Name,
+			TenantID:    access.TenantID,
+			Created:     access.Created,
+			MaxDuration: resolveNamespaceDuration(access.Created, time.Duration(1<<63-1), time.Now().Unix()),
+		})
+	}
+	return infos
+}
@@ -0,0 +1,115 @@
+package options
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/appender"
+)
+
+func TestResolveTenantIDHeaderTakesPriority(t *testing.T) {
+	r := newTestRequest("GET", "/api/graph?tenant=query-tenant")
+	r.Header.Set("X-Kiali-Tenant", "header-tenant")
+	r = mux.SetURLVars(r, map[string]string{"tenant": "path-tenant"})
+
+	if got := ResolveTenantID(r); got != "header-tenant" {
+		t.Fatalf("expected the X-Kiali-Tenant header to take priority, got %q", got)
+	}
+}
+
+func TestResolveTenantIDFallsBackToPathVar(t *testing.T) {
+	r := newTestRequest("GET", "/api/graph?tenant=query-tenant")
+	r = mux.SetURLVars(r, map[string]string{"tenant": "path-tenant"})
+
+	if got := ResolveTenantID(r); got != "path-tenant" {
+		t.Fatalf("expected the tenant path variable to be used when no header is set, got %q", got)
+	}
+}
+
+func TestResolveTenantIDFallsBackToQueryParam(t *testing.T) {
+	r := newTestRequest("GET", "/api/graph?tenant=query-tenant")
+
+	if got := ResolveTenantID(r); got != "query-tenant" {
+		t.Fatalf("expected the tenant query param to be used when no header or path var is set, got %q", got)
+	}
+}
+
+func TestResolveTenantIDDefaultsWhenUnset(t *testing.T) {
+	r := newTestRequest("GET", "/api/graph")
+
+	if got := ResolveTenantID(r); got != graph.DefaultTenantID {
+		t.Fatalf("expected the default tenant when nothing is set, got %q", got)
+	}
+}
+
+func newTestRequest(method string, target string) *http.Request {
+	r, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func TestParsePeersEmpty(t *testing.T) {
+	if peers := parsePeers(""); peers != nil {
+		t.Fatalf("expected no peers for an empty string, got %v", peers)
+	}
+}
+
+func TestParsePeersNameOnly(t *testing.T) {
+	peers := parsePeers("mesh-b")
+	want := []graph.PeerInfo{{Name: "mesh-b"}}
+	if !reflect.DeepEqual(peers, want) {
+		t.Fatalf("expected %v, got %v", want, peers)
+	}
+}
+
+func TestParsePeersNameAndCluster(t *testing.T) {
+	peers := parsePeers(" mesh-b@remote-cluster , mesh-c ")
+	want := []graph.PeerInfo{
+		{Name: "mesh-b", Cluster: "remote-cluster"},
+		{Name: "mesh-c"},
+	}
+	if !reflect.DeepEqual(peers, want) {
+		t.Fatalf("expected %v, got %v", want, peers)
+	}
+}
+
+func TestParseAppendersExplicitFederationWithoutFlagIsRejected(t *testing.T) {
+	params := url.Values{}
+	params.Set("appenders", "federation")
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("expected explicitly requesting appenders=federation without federation=true to be rejected, not silently dropped")
+		}
+	}()
+	parseAppenders(params, Options{})
+}
+
+func TestParseAppendersExplicitFederationWithFlagSucceeds(t *testing.T) {
+	params := url.Values{}
+	params.Set("appenders", "federation")
+	params.Set("federation", "true")
+
+	appenders := parseAppenders(params, Options{})
+	if len(appenders) != 1 {
+		t.Fatalf("expected exactly 1 appender (federation), got %d", len(appenders))
+	}
+}
+
+func TestParseAppendersFederationNeverRunsImplicitly(t *testing.T) {
+	// No "appenders" param at all means "run everything" except federation,
+	// which is opt-in only and must never be implied.
+	appenders := parseAppenders(url.Values{}, Options{})
+	for _, a := range appenders {
+		if _, ok := a.(appender.FederationAppender); ok {
+			t.Fatalf("expected federation to never run implicitly as part of the default appender set")
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package graph
+
+import "time"
+
+// TenantNamespaceKey scopes a namespace to a tenant. Namespace names are
+// only unique within a tenant; two tenants sharing a cluster may each have a
+// namespace named "default", and without this key an accessibility check or
+// graph query could leak nodes from one tenant's namespace into the other's.
+type TenantNamespaceKey struct {
+	TenantID string
+	Name     string
+}
+
+// DefaultTenantID is used for requests that don't carry an explicit tenant,
+// so that existing single-tenant deployments keep working unchanged.
+const DefaultTenantID string = "default"
+
+// NamespaceAccess is the value type of an accessible-namespaces set keyed by
+// TenantNamespaceKey: the tenant-scoped namespace's creation time, used to
+// bound query ranges the same way the legacy map[string]time.Time did.
+type NamespaceAccess struct {
+	Name     string
+	TenantID string
+	Created  time.Time
+}
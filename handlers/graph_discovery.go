@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/appender"
+	"github.com/kiali/kiali/graph/options"
+)
+
+// formatOf returns the requested response format, defaulting to "json".
+// "proto" is accepted but, absent a generated protobuf codec in this build,
+// is served as a textual description of the wire schema rather than a
+// binary-encoded message; it exists so clients can discover the shape a
+// future protobuf codec would use.
+func formatOf(r *http.Request) string {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		return "json"
+	}
+	return format
+}
+
+// GraphAppenders handles GET /api/graph/appenders, returning the name,
+// description, accepted params and defaults of every registered appender,
+// so a UI or third-party tool can discover graph capabilities instead of
+// hard-coding the appender list.
+func GraphAppenders(w http.ResponseWriter, r *http.Request) {
+	infos := appender.List()
+
+	switch formatOf(r) {
+	case "proto":
+		writeProtoSchema(w, "AppenderInfo", infos)
+	default:
+		writeJSON(w, infos)
+	}
+}
+
+// GraphNamespaces handles GET /api/graph/namespaces, returning the set of
+// namespaces discovered for the caller's token (including any namespaces
+// surfaced by a configured namespace discovery provider) along with their
+// creation timestamp and the resulting graph duration bound.
+func GraphNamespaces(w http.ResponseWriter, r *http.Request) {
+	token, err := tokenFromContext(r)
+	if err != nil {
+		graph.Forbidden(err.Error())
+	}
+
+	infos := options.DiscoverNamespaces(token, options.ResolveTenantID(r))
+
+	switch formatOf(r) {
+	case "proto":
+		writeProtoSchema(w, "NamespaceInfo", infos)
+	default:
+		writeJSON(w, infos)
+	}
+}
+
+func tokenFromContext(r *http.Request) (string, error) {
+	tokenContext := r.Context().Value("token")
+	if tokenContext == nil {
+		return "", fmt.Errorf("token missing in request context")
+	}
+	token, ok := tokenContext.(string)
+	if !ok {
+		return "", fmt.Errorf("token is not of type string")
+	}
+	return token, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeProtoSchema writes a textual description of the protobuf message
+// schema that v's JSON shape maps to, under the given message name. v is
+// typically a slice of structs (the shape every caller of this function
+// passes today); the schema describes the element type, nested struct
+// fields becoming nested message types.
+func writeProtoSchema(w http.ResponseWriter, messageName string, v interface{}) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+
+	t := elemType(reflect.TypeOf(v))
+	var sb strings.Builder
+	writeProtoMessage(&sb, messageName, t, map[string]bool{})
+	fmt.Fprint(w, sb.String())
+}
+
+// writeProtoMessage appends the proto message definition for t, and for any
+// struct-typed fields, to sb. seen prevents infinite recursion on
+// self-referential types and duplicate nested definitions.
+func writeProtoMessage(sb *strings.Builder, name string, t reflect.Type, seen map[string]bool) {
+	if seen[name] {
+		return
+	}
+	seen[name] = true
+
+	if t == nil || t.Kind() != reflect.Struct || t == reflect.TypeOf(time.Time{}) {
+		fmt.Fprintf(sb, "message %s {\n  %s value = 1;\n}\n\n", name, protoScalarType(t))
+		return
+	}
+
+	type nestedMessage struct {
+		name string
+		t    reflect.Type
+	}
+	var nested []nestedMessage
+
+	fmt.Fprintf(sb, "message %s {\n", name)
+	field := 0
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue // unexported
+		}
+		field++
+
+		ft := elemType(f.Type)
+		repeated := f.Type.Kind() == reflect.Slice || f.Type.Kind() == reflect.Array
+
+		var typeName string
+		switch {
+		case ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}):
+			typeName = ft.Name()
+			nested = append(nested, nestedMessage{typeName, ft})
+		case ft.Kind() == reflect.Map:
+			typeName = fmt.Sprintf("map<string, %s>", protoScalarType(ft.Elem()))
+		default:
+			typeName = protoScalarType(ft)
+		}
+
+		prefix := ""
+		if repeated {
+			prefix = "repeated "
+		}
+		fmt.Fprintf(sb, "  %s%s %s = %d;\n", prefix, typeName, protoFieldName(f.Name), field)
+	}
+	fmt.Fprintf(sb, "}\n\n")
+
+	for _, n := range nested {
+		writeProtoMessage(sb, n.name, n.t, seen)
+	}
+}
+
+// elemType unwraps pointer, slice and array types down to the type they
+// ultimately hold, e.g. []*Foo -> Foo.
+func elemType(t reflect.Type) reflect.Type {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	return t
+}
+
+// protoScalarType maps a Go scalar kind to the protobuf scalar type a
+// generated codec would use. Kinds with no sensible protobuf scalar (e.g.
+// funcs, channels) fall back to "string" so the schema stays renderable.
+func protoScalarType(t reflect.Type) string {
+	if t == nil {
+		return "string"
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return "int32"
+	case reflect.Int64:
+		return "int64"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return "uint32"
+	case reflect.Uint64:
+		return "uint64"
+	case reflect.Float32:
+		return "float"
+	case reflect.Float64:
+		return "double"
+	default:
+		return "string"
+	}
+}
+
+// protoFieldName converts a Go exported field name (e.g. "TenantID") to the
+// lower_snake_case a .proto file would use (e.g. "tenant_id").
+func protoFieldName(name string) string {
+	var sb strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := rune(name[i-1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) {
+				sb.WriteByte('_')
+			} else if i+1 < len(name) && unicode.IsLower(rune(name[i+1])) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
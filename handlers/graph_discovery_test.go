@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProtoFieldName(t *testing.T) {
+	cases := map[string]string{
+		"Name":        "name",
+		"TenantID":    "tenant_id",
+		"MaxDuration": "max_duration",
+		"Created":     "created",
+	}
+	for in, want := range cases {
+		if got := protoFieldName(in); got != want {
+			t.Errorf("protoFieldName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWriteProtoSchemaReflectsFields(t *testing.T) {
+	type Inner struct {
+		Name    string
+		Default string
+	}
+	type Outer struct {
+		Name   string
+		Params []Inner
+	}
+
+	w := httptest.NewRecorder()
+	writeProtoSchema(w, "Outer", []Outer{{Name: "serviceEntry", Params: []Inner{{Name: "x", Default: "y"}}}})
+	body := w.Body.String()
+
+	if !strings.Contains(body, "message Outer {") {
+		t.Fatalf("expected schema to define the Outer message, got:\n%s", body)
+	}
+	if !strings.Contains(body, "repeated Inner params = 2;") {
+		t.Fatalf("expected Outer.Params to be reflected as a repeated nested Inner field, got:\n%s", body)
+	}
+	if !strings.Contains(body, "message Inner {") {
+		t.Fatalf("expected the nested Inner message to be defined, got:\n%s", body)
+	}
+	if !strings.Contains(body, "string default = 2;") {
+		t.Fatalf("expected Inner.Default to be reflected as a string field, got:\n%s", body)
+	}
+}
+
+func TestWriteProtoSchemaDifferentTypesProduceDifferentSchemas(t *testing.T) {
+	type A struct{ Foo string }
+	type B struct{ Bar int64 }
+
+	wa := httptest.NewRecorder()
+	writeProtoSchema(wa, "A", []A{{Foo: "x"}})
+
+	wb := httptest.NewRecorder()
+	writeProtoSchema(wb, "B", []B{{Bar: 1}})
+
+	if wa.Body.String() == wb.Body.String() {
+		t.Fatalf("expected distinct input types to produce distinct schemas, got identical output:\n%s", wa.Body.String())
+	}
+}